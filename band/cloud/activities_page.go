@@ -0,0 +1,167 @@
+package cloud
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// ActivityQuery holds the documented /Activities query parameters.
+type ActivityQuery struct {
+	ActivityTypes          []string
+	StartTime              *time.Time
+	EndTime                *time.Time
+	IncludeMapPoints       bool
+	IncludeMinuteSummaries bool
+	DeviceID               string
+	MaxPageSize            int
+}
+
+func (q ActivityQuery) values() url.Values {
+	v := url.Values{}
+	if len(q.ActivityTypes) > 0 {
+		v.Set("activityTypes", strings.Join(q.ActivityTypes, ","))
+	}
+	if q.StartTime != nil {
+		v.Set("startTime", q.StartTime.Format(time.RFC3339))
+	}
+	if q.EndTime != nil {
+		v.Set("endTime", q.EndTime.Format(time.RFC3339))
+	}
+
+	var includes []string
+	if q.IncludeMapPoints {
+		includes = append(includes, "mapPoints")
+	}
+	if q.IncludeMinuteSummaries {
+		includes = append(includes, "minuteSummaries")
+	}
+	if len(includes) > 0 {
+		v.Set("activityIncludes", strings.Join(includes, ","))
+	}
+
+	if q.DeviceID != "" {
+		v.Set("deviceId", q.DeviceID)
+	}
+	if q.MaxPageSize > 0 {
+		v.Set("maxPageSize", strconv.Itoa(q.MaxPageSize))
+	}
+
+	return v
+}
+
+// ActivitiesPage fetches a single page of /Activities matching query. Use
+// an ActivityIterator to walk every page via Activities.NextPage.
+func (c *Client) ActivitiesPage(ctx context.Context, query ActivityQuery) (Activities, error) {
+	var activities Activities
+	req, err := c.newRequestRelative(ctx, "GET", "/Activities?"+query.values().Encode(), nil)
+	if err != nil {
+		return activities, err
+	}
+
+	resp, err := c.Do(req, &activities)
+	if err != nil {
+		return activities, err
+	}
+	resp.Body.Close()
+
+	return activities, nil
+}
+
+func (c *Client) activitiesPageAt(ctx context.Context, nextPage string) (Activities, error) {
+	var activities Activities
+	req, err := c.newRequestAbsolute(ctx, "GET", nextPage, nil)
+	if err != nil {
+		return activities, err
+	}
+
+	resp, err := c.Do(req, &activities)
+	if err != nil {
+		return activities, err
+	}
+	resp.Body.Close()
+
+	return activities, nil
+}
+
+// flattenActivities concatenates every activity category in a page into a
+// single slice, in the same order the fields are declared in Activities.
+func flattenActivities(page Activities) []Activity {
+	activities := make([]Activity, 0, page.ItemCount)
+	activities = append(activities, page.SleepActivities...)
+	activities = append(activities, page.RunActivities...)
+	activities = append(activities, page.GuidedWorkoutActivites...)
+	activities = append(activities, page.GolfActivities...)
+	activities = append(activities, page.FreePlayActivities...)
+	activities = append(activities, page.BikeActivities...)
+	return activities
+}
+
+// ActivityIterator walks every page of an Activities query, following
+// NextPage until the API stops returning one.
+type ActivityIterator struct {
+	c       *Client
+	query   ActivityQuery
+	items   []Activity
+	pos     int
+	nextURL string
+	fetched bool
+	err     error
+}
+
+// NewActivityIterator returns an iterator over every Activity matching
+// query, fetching additional pages via NextPage as needed.
+func (c *Client) NewActivityIterator(query ActivityQuery) *ActivityIterator {
+	return &ActivityIterator{c: c, query: query, pos: -1}
+}
+
+// Next advances the iterator, fetching additional pages as needed. It
+// returns false once the iteration is exhausted or an error occurs; check
+// Err to distinguish the two.
+func (it *ActivityIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.pos++
+	for it.pos >= len(it.items) {
+		if it.fetched && it.nextURL == "" {
+			return false
+		}
+
+		var (
+			page Activities
+			err  error
+		)
+		if !it.fetched {
+			page, err = it.c.ActivitiesPage(ctx, it.query)
+		} else {
+			page, err = it.c.activitiesPageAt(ctx, it.nextURL)
+		}
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.fetched = true
+		it.items = flattenActivities(page)
+		it.nextURL = page.NextPage
+		it.pos = 0
+	}
+
+	return true
+}
+
+// Value returns the Activity at the iterator's current position. It is
+// only valid to call after a call to Next that returned true.
+func (it *ActivityIterator) Value() Activity {
+	return it.items[it.pos]
+}
+
+// Err returns the error, if any, that stopped the iterator.
+func (it *ActivityIterator) Err() error {
+	return it.err
+}
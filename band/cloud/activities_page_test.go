@@ -0,0 +1,74 @@
+package cloud
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func testClient(baseURL string) *Client {
+	u, _ := url.Parse(baseURL)
+	return &Client{Client: http.DefaultClient, BaseUrl: u}
+}
+
+func TestActivityIteratorWalksAllPages(t *testing.T) {
+	var nextURL string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Activities", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Activities{
+			RunActivities: []Activity{{UserID: "u1"}},
+			ItemCount:     1,
+			NextPage:      nextURL,
+		})
+	})
+	mux.HandleFunc("/Activities/page2", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Activities{
+			RunActivities: []Activity{{UserID: "u2"}, {UserID: "u3"}},
+			ItemCount:     2,
+		})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	nextURL = srv.URL + "/Activities/page2"
+
+	it := testClient(srv.URL).NewActivityIterator(ActivityQuery{})
+
+	var got []string
+	for it.Next(context.Background()) {
+		got = append(got, it.Value().UserID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("iterator stopped with error: %v", err)
+	}
+
+	want := []string{"u1", "u2", "u3"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestActivityIteratorStopsOnFirstPageError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error":"boom"}`))
+	}))
+	defer srv.Close()
+
+	it := testClient(srv.URL).NewActivityIterator(ActivityQuery{})
+
+	if it.Next(context.Background()) {
+		t.Fatal("Next returned true despite the first page failing")
+	}
+	if it.Err() == nil {
+		t.Fatal("Err() is nil after a failed first page")
+	}
+}
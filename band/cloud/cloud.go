@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"time"
@@ -73,6 +74,10 @@ var (
 type Client struct {
 	Client  *http.Client
 	BaseUrl *url.URL
+
+	userAgent       string
+	retry           RetryPolicy
+	rawResponseSink func(*http.Request, []byte)
 }
 
 type tokenSource oauth2.Token
@@ -82,27 +87,49 @@ func (t *tokenSource) Token() (*oauth2.Token, error) {
 }
 
 type ConfigSource struct {
-	cfg *oauth2.Config
+	cfg         *oauth2.Config
+	stateSecret []byte
+	store       TokenStore
 }
 
-func NewConfigSource(cfg *oauth2.Config) *ConfigSource {
+// NewConfigSource builds a ConfigSource for the authorization code flow.
+// stateSecret signs the per-user state passed through AuthCodeURL and
+// Exchange; store is consulted by ClientFor to load and persist tokens
+// for a given user.
+func NewConfigSource(cfg *oauth2.Config, stateSecret []byte, store TokenStore) *ConfigSource {
 	return &ConfigSource{
-		cfg: cfg,
+		cfg:         cfg,
+		stateSecret: stateSecret,
+		store:       store,
 	}
 }
 
-func (c *ConfigSource) NewClient(tok *oauth2.Token) *Client {
+// NewClient builds a Client for tok, applying any ClientOptions passed.
+// By default it talks to BaseUrl with the default USER_AGENT and no
+// retries.
+func (c *ConfigSource) NewClient(tok *oauth2.Token, opts ...ClientOption) *Client {
+	cfg := newClientConfig(opts)
+
 	// TODO(ttacon): allow the config to have deadlines/timeouts
 	// (for the context)?
-	return &Client{
-		Client:  c.cfg.Client(context.Background(), tok),
-		BaseUrl: baseURL,
+	ctx := context.Background()
+	if cfg.httpClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, cfg.httpClient)
 	}
+
+	return cfg.newClient(c.cfg.Client(ctx, tok))
 }
 
-// NewRequest creates an *http.Request with the given method, url and
-// request body (if one is passed).
-func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Request, error) {
+// NewRequest creates an *http.Request with the given method, url (relative
+// to c.BaseUrl) and request body (if one is passed). It is an alias for
+// newRequestRelative, kept for backwards compatibility.
+func (c *Client) NewRequest(ctx context.Context, method, urlStr string, body interface{}) (*http.Request, error) {
+	return c.newRequestRelative(ctx, method, urlStr, body)
+}
+
+// newRequestRelative creates an *http.Request against a path relative to
+// c.BaseUrl, e.g. "/Devices".
+func (c *Client) newRequestRelative(ctx context.Context, method, urlStr string, body interface{}) (*http.Request, error) {
 	// this method is based off
 	// https://github.com/google/go-github/blob/master/github/github.go:
 	// NewRequest as it's a very nice way of doing this
@@ -119,40 +146,106 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 	if err != nil {
 		return nil, err
 	}
+
+	return c.newRequest(ctx, method, resolvedUrl.String(), body)
+}
+
+// newRequestAbsolute creates an *http.Request against urlStr verbatim,
+// without resolving it against c.BaseUrl. The Microsoft Health API hands
+// back absolute URLs for pagination (Activities.NextPage), which need to
+// be followed as-is rather than concatenated onto BaseUrl.
+func (c *Client) newRequestAbsolute(ctx context.Context, method, urlStr string, body interface{}) (*http.Request, error) {
+	return c.newRequest(ctx, method, urlStr, body)
+}
+
+func (c *Client) newRequest(ctx context.Context, method, urlStr string, body interface{}) (*http.Request, error) {
 	buf := new(bytes.Buffer)
 	if body != nil {
-		if err = json.NewEncoder(buf).Encode(body); err != nil {
+		if err := json.NewEncoder(buf).Encode(body); err != nil {
 			return nil, err
 		}
 	}
 
-	req, err := http.NewRequest(method, resolvedUrl.String(), buf)
+	req, err := http.NewRequestWithContext(ctx, method, urlStr, buf)
 	if err != nil {
 		return nil, err
 	}
 
 	// TODO(ttacon): identify which headers we should add
-	// e.g. "Accept", "Content-Type", "User-Agent", etc.
-	req.Header.Add("User-Agent", USER_AGENT)
+	// e.g. "Accept", "Content-Type", etc.
+	ua := c.userAgent
+	if ua == "" {
+		ua = USER_AGENT
+	}
+	req.Header.Add("User-Agent", ua)
 	return req, nil
 }
 
-// Do "makes" the request, and if there are no errors and resp is not nil,
-// it attempts to unmarshal the  (json) response body into resp.
+// Do "makes" the request, retrying on 5xx responses per c.retry, and if
+// there are no errors and resp is not nil, it attempts to unmarshal the
+// (json) response body into resp.
 func (c *Client) Do(req *http.Request, respStr interface{}) (*http.Response, error) {
+	maxAttempts := c.retry.Max
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	if req.Body != nil && req.GetBody == nil {
+		// req's body can't be safely re-read (it wasn't built by
+		// newRequest, so http.NewRequest didn't wire up GetBody); retrying
+		// would silently resend an already-drained, empty body.
+		maxAttempts = 1
+	}
+	backoff := c.retry.Backoff
+	if backoff == nil {
+		backoff = ExponentialJitter
+	}
+
+	var resp *http.Response
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			if req.GetBody != nil {
+				if req.Body, err = req.GetBody(); err != nil {
+					return nil, err
+				}
+			}
+			time.Sleep(backoff(attempt))
+		}
+
+		resp, err = c.do(req, respStr)
+
+		var apiErr *APIError
+		if err == nil || !errors.As(err, &apiErr) || apiErr.StatusCode < http.StatusInternalServerError {
+			return resp, err
+		}
+	}
+	return resp, err
+}
+
+// do performs a single attempt of req, without retrying.
+func (c *Client) do(req *http.Request, respStr interface{}) (*http.Response, error) {
 	resp, err := c.Client.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp, err
+	}
+
+	if c.rawResponseSink != nil {
+		c.rawResponseSink(req, body)
+	}
+
 	if resp.StatusCode > 299 || resp.StatusCode < 200 {
-		return nil, errors.New(fmt.Sprintf("http request failed, resp: %#v", resp))
+		return resp, newAPIError(resp, body)
 	}
 
 	// TODO(ttacon): maybe support passing in io.Writer as resp (downloads)?
 	if respStr != nil {
-		err = json.NewDecoder(resp.Body).Decode(respStr)
+		err = json.Unmarshal(body, respStr)
 	}
 	return resp, err
 }
@@ -164,36 +257,36 @@ const (
 	DAILY  Period = "daily"
 )
 
-func (c *Client) PeriodSummaries(period Period) (error, Summaries) {
+func (c *Client) PeriodSummaries(ctx context.Context, period Period) (Summaries, error) {
 	var summaries Summaries
-	req, err := c.NewRequest("GET", fmt.Sprintf("/Summaries/%s", period), nil)
+	req, err := c.NewRequest(ctx, "GET", fmt.Sprintf("/Summaries/%s", period), nil)
 	if err != nil {
-		return err, summaries
+		return summaries, err
 	}
 
 	resp, err := c.Do(req, &summaries)
 	if err != nil {
-		return err, summaries
+		return summaries, err
 	}
 	resp.Body.Close()
 
-	return nil, summaries
+	return summaries, nil
 }
 
-func (c *Client) Profile() (error, Profile) {
+func (c *Client) Profile(ctx context.Context) (Profile, error) {
 	var profile Profile
-	req, err := c.NewRequest("GET", "/Profile", nil)
+	req, err := c.NewRequest(ctx, "GET", "/Profile", nil)
 	if err != nil {
-		return err, profile
+		return profile, err
 	}
 
 	resp, err := c.Do(req, &profile)
 	if err != nil {
-		return err, profile
+		return profile, err
 	}
 	resp.Body.Close()
 
-	return nil, profile
+	return profile, nil
 }
 
 type Profile struct {
@@ -228,9 +321,9 @@ type DeviceProfiles struct {
 	ItemCount int      `json:"itemCount"`
 }
 
-func (c *Client) Devices() (DeviceProfiles, error) {
+func (c *Client) Devices(ctx context.Context) (DeviceProfiles, error) {
 	var devices DeviceProfiles
-	req, err := c.NewRequest("GET", "/Devices", nil)
+	req, err := c.NewRequest(ctx, "GET", "/Devices", nil)
 	if err != nil {
 		return devices, err
 	}
@@ -244,9 +337,9 @@ func (c *Client) Devices() (DeviceProfiles, error) {
 	return devices, nil
 }
 
-func (c *Client) Device(id string) (Device, error) {
+func (c *Client) Device(ctx context.Context, id string) (Device, error) {
 	var device Device
-	req, err := c.NewRequest("GET", "/Devices/"+id, nil)
+	req, err := c.NewRequest(ctx, "GET", "/Devices/"+id, nil)
 	if err != nil {
 		return device, err
 	}
@@ -260,9 +353,9 @@ func (c *Client) Device(id string) (Device, error) {
 	return device, nil
 }
 
-func (c *Client) Activities() (Activities, error) {
+func (c *Client) Activities(ctx context.Context) (Activities, error) {
 	var activities Activities
-	req, err := c.NewRequest("GET", "/Activities", nil)
+	req, err := c.NewRequest(ctx, "GET", "/Activities", nil)
 	if err != nil {
 		return activities, err
 	}
@@ -276,9 +369,9 @@ func (c *Client) Activities() (Activities, error) {
 	return activities, nil
 }
 
-func (c *Client) Activity(id string) (Activity, error) {
+func (c *Client) Activity(ctx context.Context, id string) (Activity, error) {
 	var activity Activity
-	req, err := c.NewRequest("GET", "/Activities/"+id, nil)
+	req, err := c.NewRequest(ctx, "GET", "/Activities/"+id, nil)
 	if err != nil {
 		return activity, err
 	}
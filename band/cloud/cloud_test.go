@@ -0,0 +1,94 @@
+package cloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestDoRetriesOn5xxUntilSuccess(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(Profile{})
+	}))
+	defer srv.Close()
+
+	c := testClient(srv.URL)
+	c.retry = RetryPolicy{
+		Max:     3,
+		Backoff: func(attempt int) time.Duration { return 0 },
+	}
+
+	_, err := c.Profile(context.Background())
+	if err != nil {
+		t.Fatalf("Profile returned error after retries: %v", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Fatalf("server saw %d attempts, want 3", got)
+	}
+}
+
+func TestDoStopsRetryingOnNon5xxError(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	c := testClient(srv.URL)
+	c.retry = RetryPolicy{
+		Max:     3,
+		Backoff: func(attempt int) time.Duration { return 0 },
+	}
+
+	_, err := c.Profile(context.Background())
+	if err == nil {
+		t.Fatal("Profile returned no error for a 429 response")
+	}
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("errors.Is(err, ErrRateLimited) = false, err: %v", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Fatalf("server saw %d attempts, want 1 (429 isn't retried)", got)
+	}
+}
+
+func TestDoDoesNotRetryUnreplayableBody(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := testClient(srv.URL)
+	c.retry = RetryPolicy{
+		Max:     3,
+		Backoff: func(attempt int) time.Duration { return 0 },
+	}
+
+	req, err := http.NewRequest("POST", srv.URL+"/Devices", bytes.NewReader([]byte(`{}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.GetBody = nil
+
+	if _, err := c.Do(req, nil); err == nil {
+		t.Fatal("Do returned no error for a 5xx response")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Fatalf("server saw %d attempts, want 1 (body without GetBody isn't retried)", got)
+	}
+}
@@ -0,0 +1,53 @@
+package cloud
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrRateLimited is matched by errors.Is against an *APIError whose
+// StatusCode is http.StatusTooManyRequests.
+var ErrRateLimited = errors.New("cloud: rate limited")
+
+// APIError is returned by Client.Do when the API responds with a
+// non-2xx status.
+type APIError struct {
+	StatusCode int
+	Body       []byte
+
+	// RequestID is Microsoft's request-id response header, useful when
+	// filing support tickets.
+	RequestID string
+
+	// RateLimitReset is the time the current rate-limit window resets,
+	// parsed from the X-RateLimit-Reset header if present.
+	RateLimitReset time.Time
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("cloud: request failed with status %d (request-id %s): %s", e.StatusCode, e.RequestID, e.Body)
+}
+
+// Is lets callers write errors.Is(err, cloud.ErrRateLimited).
+func (e *APIError) Is(target error) bool {
+	return target == ErrRateLimited && e.StatusCode == http.StatusTooManyRequests
+}
+
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       body,
+		RequestID:  resp.Header.Get("request-id"),
+	}
+
+	if reset := resp.Header.Get("X-RateLimit-Reset"); reset != "" {
+		if secs, err := strconv.ParseInt(reset, 10, 64); err == nil {
+			apiErr.RateLimitReset = time.Unix(secs, 0)
+		}
+	}
+
+	return apiErr
+}
@@ -0,0 +1,143 @@
+package cloud
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ttacon/microsoft/band/fhir"
+)
+
+// ToFHIR translates an Activity's summary fields into FHIR Observation
+// resources (heart rate, steps, distance, calories and sleep stages) so
+// callers can ingest band data into FHIR-speaking health platforms
+// without each writing the same translator.
+func (a Activity) ToFHIR() []fhir.Observation {
+	var obs []fhir.Observation
+
+	if a.HeartRateSummary.AverageHeartRate > 0 {
+		obs = append(obs, fhir.NewObservation(fhir.LOINCHeartRate, "Heart rate", a.UserID, a.StartTime, fhir.Quantity{
+			Value:  float64(a.HeartRateSummary.AverageHeartRate),
+			Unit:   "beats/minute",
+			System: "http://unitsofmeasure.org",
+			Code:   "/min",
+		}))
+	}
+
+	if a.TotalStepCount > 0 {
+		obs = append(obs, fhir.NewObservation(fhir.LOINCStepCount, "Number of steps", a.UserID, a.StartTime, fhir.Quantity{
+			Value:  float64(a.TotalStepCount),
+			Unit:   "steps",
+			System: "http://unitsofmeasure.org",
+			Code:   "{steps}",
+		}))
+	}
+
+	if a.DistanceSummary.TotalDistance > 0 {
+		obs = append(obs, fhir.NewObservation(fhir.LOINCDistance, "Distance walked", a.UserID, a.StartTime, fhir.Quantity{
+			Value:  float64(a.DistanceSummary.TotalDistance),
+			Unit:   "m",
+			System: "http://unitsofmeasure.org",
+			Code:   "m",
+		}))
+	}
+
+	if a.CaloriesBurnedSummary.TotalCalories > 0 {
+		obs = append(obs, fhir.NewObservation(fhir.LOINCCalories, "Calories burned", a.UserID, a.StartTime, fhir.Quantity{
+			Value:  float64(a.CaloriesBurnedSummary.TotalCalories),
+			Unit:   "kcal",
+			System: "http://unitsofmeasure.org",
+			Code:   "kcal",
+		}))
+	}
+
+	if a.SleepDuration != "" {
+		obs = append(obs, sleepStageObservation(a.UserID, a.StartTime, "asleep", a.SleepDuration))
+	}
+
+	if a.AwakeDuration != "" {
+		obs = append(obs, sleepStageObservation(a.UserID, a.StartTime, "awake", a.AwakeDuration))
+	}
+
+	return obs
+}
+
+// sleepStageObservation builds a LOINCSleepTime Observation for stage
+// ("asleep"/"awake"), carrying duration as a structured valueQuantity in
+// seconds when it parses as an ISO 8601 duration (the format the
+// Microsoft Health API uses for *Duration fields); if it doesn't parse,
+// the raw string is kept in Code.Text rather than silently dropped.
+func sleepStageObservation(userID string, t *time.Time, stage, duration string) fhir.Observation {
+	obs := fhir.NewObservation(fhir.LOINCSleepTime, "Sleep status: "+stage, userID, t, fhir.Quantity{})
+
+	d, err := parseISO8601Duration(duration)
+	if err != nil {
+		obs.Code.Text = duration
+		return obs
+	}
+
+	obs.ValueQuantity = &fhir.Quantity{
+		Value:  d.Seconds(),
+		Unit:   "s",
+		System: "http://unitsofmeasure.org",
+		Code:   "s",
+	}
+	return obs
+}
+
+// parseISO8601Duration parses the PnYnMnDTnHnMnS form of ISO 8601
+// durations the Microsoft Health API uses for its *Duration fields.
+func parseISO8601Duration(s string) (time.Duration, error) {
+	if len(s) < 2 || s[0] != 'P' {
+		return 0, fmt.Errorf("fhir: not an ISO 8601 duration: %q", s)
+	}
+
+	var (
+		total  time.Duration
+		num    strings.Builder
+		inTime bool
+	)
+
+	for _, r := range s[1:] {
+		switch {
+		case r == 'T':
+			inTime = true
+		case r == '.' || (r >= '0' && r <= '9'):
+			num.WriteRune(r)
+		default:
+			val, err := strconv.ParseFloat(num.String(), 64)
+			if err != nil {
+				return 0, fmt.Errorf("fhir: invalid ISO 8601 duration %q: %v", s, err)
+			}
+			num.Reset()
+
+			switch r {
+			case 'Y':
+				total += time.Duration(val * 365.25 * 24 * float64(time.Hour))
+			case 'W':
+				total += time.Duration(val * 7 * 24 * float64(time.Hour))
+			case 'D':
+				total += time.Duration(val * 24 * float64(time.Hour))
+			case 'H':
+				total += time.Duration(val * float64(time.Hour))
+			case 'M':
+				if inTime {
+					total += time.Duration(val * float64(time.Minute))
+				} else {
+					total += time.Duration(val * 30 * 24 * float64(time.Hour))
+				}
+			case 'S':
+				total += time.Duration(val * float64(time.Second))
+			default:
+				return 0, fmt.Errorf("fhir: unrecognized ISO 8601 duration unit %q in %q", r, s)
+			}
+		}
+	}
+
+	if num.Len() > 0 {
+		return 0, fmt.Errorf("fhir: invalid ISO 8601 duration %q: trailing %q has no unit", s, num.String())
+	}
+
+	return total, nil
+}
@@ -0,0 +1,61 @@
+package cloud
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseISO8601Duration(t *testing.T) {
+	cases := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"PT1H30M", time.Hour + 30*time.Minute},
+		{"PT45S", 45 * time.Second},
+		{"P1D", 24 * time.Hour},
+		{"PT0S", 0},
+	}
+
+	for _, c := range cases {
+		got, err := parseISO8601Duration(c.in)
+		if err != nil {
+			t.Fatalf("parseISO8601Duration(%q) returned error: %v", c.in, err)
+		}
+		if got != c.want {
+			t.Fatalf("parseISO8601Duration(%q) = %v, want %v", c.in, got, c.want)
+		}
+	}
+}
+
+func TestParseISO8601DurationRejectsGarbage(t *testing.T) {
+	if _, err := parseISO8601Duration("not-a-duration"); err == nil {
+		t.Fatal("parseISO8601Duration accepted a non-ISO-8601 string")
+	}
+}
+
+func TestParseISO8601DurationRejectsTrailingDigits(t *testing.T) {
+	if _, err := parseISO8601Duration("PT1H30"); err == nil {
+		t.Fatal("parseISO8601Duration accepted a truncated duration with a dangling unitless number")
+	}
+}
+
+func TestActivityToFHIRKeepsSleepDurationValue(t *testing.T) {
+	a := Activity{
+		UserID:        "u1",
+		SleepDuration: "PT6H30M",
+	}
+
+	obs := a.ToFHIR()
+	if len(obs) != 1 {
+		t.Fatalf("got %d observations, want 1", len(obs))
+	}
+
+	got := obs[0]
+	if got.ValueQuantity == nil {
+		t.Fatal("sleep observation has no ValueQuantity; duration was dropped")
+	}
+	want := (6*time.Hour + 30*time.Minute).Seconds()
+	if got.ValueQuantity.Value != want {
+		t.Fatalf("ValueQuantity.Value = %v, want %v", got.ValueQuantity.Value, want)
+	}
+}
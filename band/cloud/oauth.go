@@ -0,0 +1,134 @@
+package cloud
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+)
+
+// TokenStore persists per-user OAuth tokens so a multi-user service
+// doesn't have to reinvent token storage on top of ConfigSource.
+type TokenStore interface {
+	Get(user string) (*oauth2.Token, error)
+	Put(user string, tok *oauth2.Token) error
+}
+
+// AuthCodeURL returns the URL to redirect user to in order to start the
+// authorization code flow. The returned state embeds a signature over
+// user so Exchange can recover it and reject forged callbacks.
+func (c *ConfigSource) AuthCodeURL(user string) string {
+	return c.cfg.AuthCodeURL(c.signState(user))
+}
+
+// Exchange validates the signed state on the callback request and, if it
+// checks out, exchanges the authorization code for a token. It returns
+// the user the state was signed for so the caller can persist the token.
+func (c *ConfigSource) Exchange(ctx context.Context, r *http.Request) (string, *oauth2.Token, error) {
+	if err := r.ParseForm(); err != nil {
+		return "", nil, err
+	}
+
+	user, err := c.verifyState(r.FormValue("state"))
+	if err != nil {
+		return "", nil, err
+	}
+
+	tok, err := c.cfg.Exchange(ctx, r.FormValue("code"))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return user, tok, nil
+}
+
+// ClientFor builds a Client for user using the token held in the
+// ConfigSource's TokenStore, refreshing it as needed and writing any
+// refreshed token back to the store. Any ClientOptions passed apply the
+// same way they do to NewClient.
+func (c *ConfigSource) ClientFor(ctx context.Context, user string, opts ...ClientOption) (*Client, error) {
+	tok, err := c.store.Get(user)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := newClientConfig(opts)
+	if cfg.httpClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, cfg.httpClient)
+	}
+
+	ts := &storingTokenSource{
+		user:   user,
+		store:  c.store,
+		source: c.cfg.TokenSource(ctx, tok),
+	}
+
+	return cfg.newClient(oauth2.NewClient(ctx, ts)), nil
+}
+
+// storingTokenSource wraps an oauth2.TokenSource, persisting every token
+// it hands out (including refreshed ones) back to a TokenStore.
+type storingTokenSource struct {
+	user   string
+	store  TokenStore
+	source oauth2.TokenSource
+}
+
+func (s *storingTokenSource) Token() (*oauth2.Token, error) {
+	tok, err := s.source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Put(s.user, tok); err != nil {
+		return nil, err
+	}
+
+	return tok, nil
+}
+
+// signState produces a "base64url(user).base64url(signature)" state
+// token. Both halves are base64url-encoded (and so are themselves
+// "."-free) before being joined, so splitting on the first "." in
+// verifyState is unambiguous even when user itself contains dots, e.g.
+// an email address.
+func (c *ConfigSource) signState(user string) string {
+	encodedUser := base64.RawURLEncoding.EncodeToString([]byte(user))
+	encodedSig := base64.RawURLEncoding.EncodeToString(c.signature(user))
+	return encodedUser + "." + encodedSig
+}
+
+func (c *ConfigSource) verifyState(state string) (string, error) {
+	parts := strings.SplitN(state, ".", 2)
+	if len(parts) != 2 {
+		return "", errors.New("cloud: malformed oauth state")
+	}
+
+	userBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", errors.New("cloud: invalid oauth state encoding")
+	}
+	user := string(userBytes)
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.New("cloud: invalid oauth state signature encoding")
+	}
+
+	if !hmac.Equal(sig, c.signature(user)) {
+		return "", errors.New("cloud: oauth state signature mismatch")
+	}
+
+	return user, nil
+}
+
+func (c *ConfigSource) signature(user string) []byte {
+	mac := hmac.New(sha256.New, c.stateSecret)
+	mac.Write([]byte(user))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,67 @@
+package cloud
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func testConfigSource(secret []byte) *ConfigSource {
+	return NewConfigSource(&oauth2.Config{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		Endpoint: oauth2.Endpoint{
+			TokenURL: "http://127.0.0.1:0/token",
+		},
+	}, secret, nil)
+}
+
+func TestSignStateVerifyStateRoundTrip(t *testing.T) {
+	cs := testConfigSource([]byte("secret"))
+
+	// jane.doe@example.com is exactly the dotted-user-id shape that used
+	// to get truncated by the naive "user.signature" split.
+	users := []string{"user-123", "jane.doe@example.com", "a.b.c.d@example.co.uk"}
+	for _, user := range users {
+		state := cs.signState(user)
+		got, err := cs.verifyState(state)
+		if err != nil {
+			t.Fatalf("verifyState(signState(%q)) returned error: %v", user, err)
+		}
+		if got != user {
+			t.Fatalf("verifyState(signState(%q)) = %q, want %q", user, got, user)
+		}
+	}
+}
+
+func TestVerifyStateRejectsTamperedSignature(t *testing.T) {
+	cs := testConfigSource([]byte("secret"))
+
+	state := cs.signState("jane.doe@example.com")
+	tampered := state[:len(state)-1] + "x"
+
+	if _, err := cs.verifyState(tampered); err == nil {
+		t.Fatal("verifyState accepted a tampered state")
+	}
+}
+
+func TestExchangeRejectsTamperedState(t *testing.T) {
+	cs := testConfigSource([]byte("secret"))
+
+	state := cs.signState("jane.doe@example.com")
+	tampered := state[:len(state)-1] + "x"
+
+	r := httptest.NewRequest("GET", "/callback?state="+url.QueryEscape(tampered)+"&code=abc", nil)
+
+	_, _, err := cs.Exchange(context.Background(), r)
+	if err == nil {
+		t.Fatal("Exchange accepted a request with a tampered state")
+	}
+	if !strings.Contains(err.Error(), "oauth state") {
+		t.Fatalf("Exchange returned unexpected error: %v", err)
+	}
+}
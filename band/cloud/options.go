@@ -0,0 +1,98 @@
+package cloud
+
+import (
+	"math/rand"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// clientConfig accumulates ClientOptions before a Client is built; it
+// exists so WithHTTPClient can be threaded through oauth2's client
+// wrapping instead of stomping the already-authenticated http.Client.
+type clientConfig struct {
+	baseURL         *url.URL
+	userAgent       string
+	httpClient      *http.Client
+	retry           RetryPolicy
+	rawResponseSink func(*http.Request, []byte)
+}
+
+// newClientConfig applies opts over the package defaults, used by every
+// Client constructor (NewClient, ClientFor) so they stay configurable the
+// same way.
+func newClientConfig(opts []ClientOption) *clientConfig {
+	cfg := &clientConfig{
+		baseURL:   baseURL,
+		userAgent: USER_AGENT,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// newClient builds a Client from cfg around the given (already
+// OAuth2-wrapped) http.Client.
+func (cfg *clientConfig) newClient(httpClient *http.Client) *Client {
+	return &Client{
+		Client:          httpClient,
+		BaseUrl:         cfg.baseURL,
+		userAgent:       cfg.userAgent,
+		retry:           cfg.retry,
+		rawResponseSink: cfg.rawResponseSink,
+	}
+}
+
+// ClientOption configures a Client built by ConfigSource.NewClient.
+type ClientOption func(*clientConfig)
+
+// WithBaseURL overrides the default Microsoft Health API base URL, e.g.
+// to point at a test server.
+func WithBaseURL(u *url.URL) ClientOption {
+	return func(cfg *clientConfig) { cfg.baseURL = u }
+}
+
+// WithUserAgent overrides the default User-Agent header.
+func WithUserAgent(ua string) ClientOption {
+	return func(cfg *clientConfig) { cfg.userAgent = ua }
+}
+
+// WithHTTPClient supplies the underlying *http.Client used to make
+// requests, before OAuth2 wraps it to attach credentials.
+func WithHTTPClient(h *http.Client) ClientOption {
+	return func(cfg *clientConfig) { cfg.httpClient = h }
+}
+
+// WithRetry makes the Client retry requests that fail with a 5xx
+// response, per p.
+func WithRetry(p RetryPolicy) ClientOption {
+	return func(cfg *clientConfig) { cfg.retry = p }
+}
+
+// WithRawResponseSink registers a callback invoked with every request and
+// its raw (pre-decode) response body, useful for debugging undocumented
+// field drift in the API.
+func WithRawResponseSink(sink func(*http.Request, []byte)) ClientOption {
+	return func(cfg *clientConfig) { cfg.rawResponseSink = sink }
+}
+
+// RetryPolicy controls how Client.Do retries failed requests.
+type RetryPolicy struct {
+	// Max is the maximum number of attempts, including the first. A
+	// Max of 0 means no retries.
+	Max int
+	// Backoff computes the delay before a given attempt (1-indexed).
+	// Defaults to ExponentialJitter if nil.
+	Backoff BackoffPolicy
+}
+
+// BackoffPolicy computes how long to wait before retry attempt.
+type BackoffPolicy func(attempt int) time.Duration
+
+// ExponentialJitter doubles the base delay with each attempt and adds a
+// random jitter, to avoid a thundering herd of retries.
+func ExponentialJitter(attempt int) time.Duration {
+	base := 100 * time.Millisecond * time.Duration(int64(1)<<uint(attempt))
+	return base + time.Duration(rand.Int63n(int64(base)))
+}
@@ -0,0 +1,92 @@
+package cloud
+
+import (
+	"golang.org/x/net/context"
+	"golang.org/x/sync/errgroup"
+)
+
+// SnapshotOptions controls which period summaries a Snapshot fetches
+// alongside the profile, devices and activities.
+type SnapshotOptions struct {
+	Periods []Period
+}
+
+// Snapshot is the result of fanning out Profile, Devices, Activities and
+// PeriodSummaries concurrently. Each resource gets its own error slot so
+// that one failing call doesn't discard the data the others fetched.
+type Snapshot struct {
+	Profile    Profile
+	ProfileErr error
+
+	Devices    DeviceProfiles
+	DevicesErr error
+
+	Activities    Activities
+	ActivitiesErr error
+
+	PeriodSummaries    map[Period]Summaries
+	PeriodSummariesErr map[Period]error
+}
+
+type periodSummaryResult struct {
+	period    Period
+	summaries Summaries
+	err       error
+}
+
+// Snapshot fetches Profile, Devices, Activities and the requested
+// PeriodSummaries concurrently, respecting ctx for cancellation and
+// timeouts. It returns as soon as all requests have completed. A non-nil
+// error means ctx was cancelled or timed out before that happened;
+// per-call failures don't fail Snapshot itself and are reported through
+// the per-field Err fields instead.
+func (c *Client) Snapshot(ctx context.Context, opts SnapshotOptions) (*Snapshot, error) {
+	snap := &Snapshot{
+		PeriodSummaries:    make(map[Period]Summaries, len(opts.Periods)),
+		PeriodSummariesErr: make(map[Period]error),
+	}
+
+	g, groupCtx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		snap.Profile, snap.ProfileErr = c.Profile(groupCtx)
+		return nil
+	})
+
+	g.Go(func() error {
+		snap.Devices, snap.DevicesErr = c.Devices(groupCtx)
+		return nil
+	})
+
+	g.Go(func() error {
+		snap.Activities, snap.ActivitiesErr = c.Activities(groupCtx)
+		return nil
+	})
+
+	results := make([]periodSummaryResult, len(opts.Periods))
+	for i, period := range opts.Periods {
+		i, period := i, period
+		g.Go(func() error {
+			summaries, err := c.PeriodSummaries(groupCtx, period)
+			results[i] = periodSummaryResult{period: period, summaries: summaries, err: err}
+			return nil
+		})
+	}
+
+	g.Wait()
+	// Check the caller's own ctx, not groupCtx: errgroup cancels groupCtx
+	// unconditionally once Wait returns (even on success), so it can't be
+	// used to detect whether ctx itself was cancelled or timed out.
+	if err := ctx.Err(); err != nil {
+		return snap, err
+	}
+
+	for _, r := range results {
+		snap.PeriodSummaries[r.period] = r.summaries
+		if r.err != nil {
+			snap.PeriodSummariesErr[r.period] = r.err
+		}
+	}
+
+	return snap, nil
+}
@@ -0,0 +1,74 @@
+package cloud
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// SubscriptionRequest is the body sent to create a new webhook
+// subscription against a resource such as "Activities" or "Summaries".
+type SubscriptionRequest struct {
+	CallbackURL string `json:"callbackUrl"`
+	Resource    string `json:"resource"`
+}
+
+type Subscription struct {
+	ID          string     `json:"id"`
+	CallbackURL string     `json:"callbackUrl"`
+	Resource    string     `json:"resource"`
+	UserID      string     `json:"userId"`
+	CreatedDate *time.Time `json:"createdDate"`
+}
+
+type Subscriptions struct {
+	Subscriptions []Subscription `json:"subscriptions"`
+	ItemCount     int            `json:"itemCount"`
+}
+
+func (c *Client) CreateSubscription(ctx context.Context, sub SubscriptionRequest) (Subscription, error) {
+	var created Subscription
+	req, err := c.NewRequest(ctx, "POST", "/Subscriptions", sub)
+	if err != nil {
+		return created, err
+	}
+
+	resp, err := c.Do(req, &created)
+	if err != nil {
+		return created, err
+	}
+	resp.Body.Close()
+
+	return created, nil
+}
+
+func (c *Client) ListSubscriptions(ctx context.Context) (Subscriptions, error) {
+	var subs Subscriptions
+	req, err := c.NewRequest(ctx, "GET", "/Subscriptions", nil)
+	if err != nil {
+		return subs, err
+	}
+
+	resp, err := c.Do(req, &subs)
+	if err != nil {
+		return subs, err
+	}
+	resp.Body.Close()
+
+	return subs, nil
+}
+
+func (c *Client) DeleteSubscription(ctx context.Context, id string) error {
+	req, err := c.NewRequest(ctx, "DELETE", "/Subscriptions/"+id, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Do(req, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
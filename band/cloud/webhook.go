@@ -0,0 +1,70 @@
+package cloud
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+)
+
+// signatureHeader is the header Microsoft Health signs webhook callback
+// bodies with, following the same scheme Fitbit uses for its own
+// subscription notifications.
+const signatureHeader = "X-Hub-Signature"
+
+// VerifyWebhook wraps next with HMAC-SHA1 verification of the incoming
+// webhook callback body against clientSecret. Requests with a missing or
+// mismatched signature are rejected with 401 and never reach next; on
+// success the body is restored so next can decode it again.
+func VerifyWebhook(clientSecret string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body.Close()
+
+		sig, err := base64.StdEncoding.DecodeString(r.Header.Get(signatureHeader))
+		if err != nil {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		mac := hmac.New(sha1.New, []byte(clientSecret+"&"))
+		mac.Write(body)
+
+		if !hmac.Equal(sig, mac.Sum(nil)) {
+			http.Error(w, "signature mismatch", http.StatusUnauthorized)
+			return
+		}
+
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Notification is a single event within a webhook callback payload.
+type Notification struct {
+	UserID     string `json:"userId"`
+	DeviceID   string `json:"deviceId"`
+	ActivityID string `json:"activityId"`
+	Type       string `json:"type"`
+}
+
+type notificationBatch struct {
+	Notifications []Notification `json:"notifications"`
+}
+
+// ParseNotifications decodes the batched notification payload delivered
+// to a webhook callback once VerifyWebhook has let it through.
+func ParseNotifications(body []byte) ([]Notification, error) {
+	var batch notificationBatch
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return nil, err
+	}
+	return batch.Notifications, nil
+}
@@ -0,0 +1,65 @@
+package cloud
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func signBody(secret string, body []byte) string {
+	mac := hmac.New(sha1.New, []byte(secret+"&"))
+	mac.Write(body)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyWebhookAcceptsValidSignature(t *testing.T) {
+	const secret = "shh"
+	body := []byte(`{"notifications":[{"userId":"u1"}]}`)
+
+	var gotBody []byte
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	r := httptest.NewRequest("POST", "/webhook", bytes.NewReader(body))
+	r.Header.Set(signatureHeader, signBody(secret, body))
+	w := httptest.NewRecorder()
+
+	VerifyWebhook(secret, next).ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusOK)
+	}
+	if !bytes.Equal(gotBody, body) {
+		t.Fatalf("next saw body %q, want %q", gotBody, body)
+	}
+}
+
+func TestVerifyWebhookRejectsForgedSignature(t *testing.T) {
+	const secret = "shh"
+	body := []byte(`{"notifications":[{"userId":"u1"}]}`)
+
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	r := httptest.NewRequest("POST", "/webhook", bytes.NewReader(body))
+	r.Header.Set(signatureHeader, signBody("wrong-secret", body))
+	w := httptest.NewRecorder()
+
+	VerifyWebhook(secret, next).ServeHTTP(w, r)
+
+	if called {
+		t.Fatal("next was invoked despite a forged signature")
+	}
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
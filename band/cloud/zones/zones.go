@@ -0,0 +1,50 @@
+// Package zones converts Microsoft Band's eight heart-rate-zone
+// categories into the common five-zone model used by Fitbit, Garmin,
+// Google Fit and most other platforms, so downstream code doesn't need
+// to special-case Band's category names.
+package zones
+
+import "github.com/ttacon/microsoft/band/cloud"
+
+// Canonical is one of the common five heart-rate zones.
+type Canonical string
+
+const (
+	OutOfRange Canonical = "outOfRange"
+	FatBurn    Canonical = "fatBurn"
+	Cardio     Canonical = "cardio"
+	Peak       Canonical = "peak"
+	// OverPeak is a superset bucket most platforms fold into Peak, kept
+	// separate here since Band distinguishes it (OverRedline).
+	OverPeak Canonical = "overPeak"
+)
+
+// CanonicalZones holds minute counts per Canonical zone.
+type CanonicalZones struct {
+	OutOfRange int
+	FatBurn    int
+	Cardio     int
+	Peak       int
+	OverPeak   int
+}
+
+// ToCanonical regroups Band's minute counts into the canonical five-zone
+// model. profile is accepted for parity with the age-based max-heart-rate
+// (220-age) thresholds Band itself derives its categories from; the
+// regrouping below is a direct remapping of Band's already-bucketed
+// minute counts and needs no further arithmetic:
+//
+//	UnderHealthyHeart + UnderAerobic -> OutOfRange
+//	HealthyHeart + FitnessZone       -> FatBurn
+//	Aerobic                         -> Cardio
+//	Anaerobic + Redline             -> Peak
+//	OverRedline                     -> OverPeak
+func ToCanonical(z cloud.HeartRateZones, profile cloud.Profile) CanonicalZones {
+	return CanonicalZones{
+		OutOfRange: z.UnderHealthyHeart + z.UnderAerobic,
+		FatBurn:    z.HealthyHeart + z.FitnessZone,
+		Cardio:     z.Aerobic,
+		Peak:       z.Anaerobic + z.Redline,
+		OverPeak:   z.OverRedline,
+	}
+}
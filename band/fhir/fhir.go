@@ -0,0 +1,73 @@
+// Package fhir holds the minimal subset of the FHIR Observation resource
+// needed to export band activity data into health platforms that speak
+// FHIR (or the closely related Google Fit data model), keyed by LOINC
+// codes where one is commonly used for the measurement in question.
+package fhir
+
+import "time"
+
+// LOINCSystem is the coding system URI used for every Coding below.
+const LOINCSystem = "http://loinc.org"
+
+// LOINC codes for the measurements Activity.ToFHIR emits. These are the
+// codes most commonly used for consumer activity-tracker data; consult
+// loinc.org if a downstream system expects something more specific.
+const (
+	LOINCHeartRate = "8867-4"  // Heart rate
+	LOINCStepCount = "55423-8" // Number of steps in unspecified time Pedometer
+	LOINCDistance  = "55430-3" // Distance walked
+	LOINCCalories  = "41981-2" // Calories burned
+	LOINCSleepTime = "93832-4" // Sleep status
+)
+
+// Observation is a trimmed-down FHIR Observation resource: enough fields
+// to carry a single timestamped measurement and its subject.
+type Observation struct {
+	ResourceType      string          `json:"resourceType"`
+	Status            string          `json:"status"`
+	Code              CodeableConcept `json:"code"`
+	Subject           *Reference      `json:"subject,omitempty"`
+	EffectiveDateTime *time.Time      `json:"effectiveDateTime,omitempty"`
+	ValueQuantity     *Quantity       `json:"valueQuantity,omitempty"`
+}
+
+type CodeableConcept struct {
+	Coding []Coding `json:"coding"`
+	Text   string   `json:"text,omitempty"`
+}
+
+type Coding struct {
+	System  string `json:"system"`
+	Code    string `json:"code"`
+	Display string `json:"display,omitempty"`
+}
+
+type Quantity struct {
+	Value  float64 `json:"value"`
+	Unit   string  `json:"unit"`
+	System string  `json:"system,omitempty"`
+	Code   string  `json:"code,omitempty"`
+}
+
+// Reference points to another FHIR resource, e.g. "Patient/123".
+type Reference struct {
+	Reference string `json:"reference"`
+}
+
+// NewObservation builds an Observation with status "final" for the given
+// LOINC code, subject and value.
+func NewObservation(loincCode, display, userID string, t *time.Time, value Quantity) Observation {
+	obs := Observation{
+		ResourceType: "Observation",
+		Status:       "final",
+		Code: CodeableConcept{
+			Coding: []Coding{{System: LOINCSystem, Code: loincCode, Display: display}},
+		},
+		EffectiveDateTime: t,
+		ValueQuantity:     &value,
+	}
+	if userID != "" {
+		obs.Subject = &Reference{Reference: "Patient/" + userID}
+	}
+	return obs
+}